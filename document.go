@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ubuygold/deeplx-cli/internal/cache"
+	"github.com/ubuygold/deeplx-cli/internal/pool"
+)
+
+// docOptions carries the tag-handling configuration for a single document
+// translation run.
+type docOptions struct {
+	pool               *pool.Pool
+	cache              *cache.Store
+	sourceLang         string
+	targetLang         string
+	tagHandling        string
+	splittingTags      []string
+	nonSplittingTags   []string
+	ignoreTags         []string
+	preserveFormatting bool
+}
+
+// translateDocument translates the file at inPath and writes the result to
+// outPath, dispatching on file extension so inline markup (HTML/XML tags,
+// SRT timestamps) survives the round trip.
+func translateDocument(inPath, outPath string, opts docOptions) error {
+	raw, err := os.ReadFile(inPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", inPath, err)
+	}
+
+	var out string
+	switch strings.ToLower(filepath.Ext(inPath)) {
+	case ".srt":
+		out, err = translateSRT(string(raw), opts)
+	case ".html":
+		opts.tagHandling = "html"
+		out, err = translateChunked(string(raw), opts)
+	default: // .txt, .md, and anything else: treat as plain text
+		out, err = translateChunked(string(raw), opts)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to translate %s: %w", inPath, err)
+	}
+
+	if err := os.WriteFile(outPath, []byte(out), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+	return nil
+}
+
+// translateChunked splits text into API-sized chunks, translates each while
+// preserving original whitespace between chunks, and reassembles the result.
+func translateChunked(text string, opts docOptions) (string, error) {
+	chunks := splitParagraphs(text, maxChunkRunes)
+	if opts.tagHandling != "" {
+		// splitParagraphs/splitSentences know nothing about markup, so a tag
+		// can land right on a chunk boundary; stitch any split tag back
+		// together before sending chunks to the API.
+		chunks = mergeSplitTags(chunks)
+	}
+
+	var sb strings.Builder
+	for _, chunk := range chunks {
+		trimmed := strings.TrimRight(chunk, "\n")
+		trailing := chunk[len(trimmed):]
+		if strings.TrimSpace(trimmed) == "" {
+			sb.WriteString(chunk)
+			continue
+		}
+
+		translated, err := translateRequest(TranslationRequest{
+			Text:               trimmed,
+			SourceLang:         opts.sourceLang,
+			TargetLang:         opts.targetLang,
+			TagHandling:        opts.tagHandling,
+			SplittingTags:      opts.splittingTags,
+			NonSplittingTags:   opts.nonSplittingTags,
+			IgnoreTags:         opts.ignoreTags,
+			PreserveFormatting: opts.preserveFormatting,
+		}, opts.pool, opts.cache)
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(translated)
+		sb.WriteString(trailing)
+	}
+	return sb.String(), nil
+}
+
+var srtCueTextLine = regexp.MustCompile(`^\d+$|^\d{2}:\d{2}:\d{2},\d{3} --> \d{2}:\d{2}:\d{2},\d{3}`)
+
+// translateSRT translates only the cue text of an SRT subtitle file,
+// leaving cue indices and timestamps untouched.
+func translateSRT(content string, opts docOptions) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	var sb strings.Builder
+	for i, line := range lines {
+		if line == "" || srtCueTextLine.MatchString(strings.TrimSpace(line)) {
+			sb.WriteString(line)
+		} else {
+			translated, err := translateRequest(TranslationRequest{
+				Text:               line,
+				SourceLang:         opts.sourceLang,
+				TargetLang:         opts.targetLang,
+				PreserveFormatting: opts.preserveFormatting,
+			}, opts.pool, opts.cache)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(translated)
+		}
+		if i < len(lines)-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ubuygold/deeplx-cli/internal/pool"
+)
+
+// stubTranslateServer echoes back the request text upper-cased so tests can
+// tell translated cue/chunk text apart from passthrough text.
+func stubTranslateServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TranslationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		json.NewEncoder(w).Encode(TranslationResponse{
+			Code: 200,
+			Data: strings.ToUpper(req.Text),
+		})
+	}))
+}
+
+func TestTranslateDocumentSRTPreservesTimestamps(t *testing.T) {
+	server := stubTranslateServer(t)
+	defer server.Close()
+
+	srt := "1\n00:00:00,000 --> 00:00:02,000\nhello\n\n2\n00:00:02,000 --> 00:00:04,000\nworld\n"
+
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.srt")
+	outPath := filepath.Join(dir, "out.srt")
+	if err := os.WriteFile(inPath, []byte(srt), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	opts := docOptions{
+		pool:       pool.New([]pool.Endpoint{{URL: server.URL, Weight: 1}}, pool.StrategyFailover),
+		sourceLang: "auto",
+		targetLang: "ZH",
+	}
+	if err := translateDocument(inPath, outPath, opts); err != nil {
+		t.Fatalf("translateDocument failed: %v", err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+
+	want := "1\n00:00:00,000 --> 00:00:02,000\nHELLO\n\n2\n00:00:02,000 --> 00:00:04,000\nWORLD\n"
+	if string(out) != want {
+		t.Errorf("unexpected output:\ngot:  %q\nwant: %q", string(out), want)
+	}
+}
+
+func TestTranslateDocumentChunkedTextRoundTrip(t *testing.T) {
+	server := stubTranslateServer(t)
+	defer server.Close()
+
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.txt")
+	outPath := filepath.Join(dir, "out.txt")
+	if err := os.WriteFile(inPath, []byte("hello world"), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	opts := docOptions{
+		pool:       pool.New([]pool.Endpoint{{URL: server.URL, Weight: 1}}, pool.StrategyFailover),
+		sourceLang: "auto",
+		targetLang: "ZH",
+	}
+	if err := translateDocument(inPath, outPath, opts); err != nil {
+		t.Fatalf("translateDocument failed: %v", err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(out) != "HELLO WORLD" {
+		t.Errorf("expected 'HELLO WORLD', got %q", string(out))
+	}
+}
+
+func TestTranslateDocumentHTMLUsesHTMLTagHandling(t *testing.T) {
+	var gotTagHandling string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TranslationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		gotTagHandling = req.TagHandling
+		json.NewEncoder(w).Encode(TranslationResponse{Code: 200, Data: req.Text})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.html")
+	outPath := filepath.Join(dir, "out.html")
+	if err := os.WriteFile(inPath, []byte("<p>hello</p>"), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	opts := docOptions{
+		pool:       pool.New([]pool.Endpoint{{URL: server.URL, Weight: 1}}, pool.StrategyFailover),
+		sourceLang: "auto",
+		targetLang: "ZH",
+	}
+	if err := translateDocument(inPath, outPath, opts); err != nil {
+		t.Fatalf("translateDocument failed: %v", err)
+	}
+	if gotTagHandling != "html" {
+		t.Errorf("expected tag_handling 'html', got %q", gotTagHandling)
+	}
+}
+
+func TestTranslateDocumentHTMLDoesNotSplitTagsAcrossChunks(t *testing.T) {
+	var gotChunks []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TranslationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		gotChunks = append(gotChunks, req.Text)
+		json.NewEncoder(w).Encode(TranslationResponse{Code: 200, Data: req.Text})
+	}))
+	defer server.Close()
+
+	// A single unbroken "paragraph" (no blank lines) just over maxChunkRunes,
+	// with a sentence-ending period sitting inside a tag's attribute value —
+	// the naive sentence splitter would cut right there.
+	prefix := strings.Repeat("a", 3950)
+	tag := `<meta name="description" content="Welcome. Read more">`
+	suffix := strings.Repeat("b", 200)
+	html := prefix + tag + suffix
+
+	dir := t.TempDir()
+	inPath := filepath.Join(dir, "in.html")
+	outPath := filepath.Join(dir, "out.html")
+	if err := os.WriteFile(inPath, []byte(html), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	opts := docOptions{
+		pool:       pool.New([]pool.Endpoint{{URL: server.URL, Weight: 1}}, pool.StrategyFailover),
+		sourceLang: "auto",
+		targetLang: "ZH",
+	}
+	if err := translateDocument(inPath, outPath, opts); err != nil {
+		t.Fatalf("translateDocument failed: %v", err)
+	}
+
+	if len(gotChunks) < 2 {
+		t.Fatalf("expected fixture to require more than one chunk, got %d", len(gotChunks))
+	}
+	for _, c := range gotChunks {
+		if endsInOpenTag(c) {
+			t.Errorf("chunk sent to upstream ends mid-tag: %q", c)
+		}
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(out), tag) {
+		t.Errorf("expected output to contain the original tag intact, got %q", string(out))
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	glossaryFileName    = ".deeplx-cli.glossary.yml"
+	glossarySentinelTag = "deeplx-term"
+)
+
+// Glossary maps a "SOURCE->TARGET" language pair (see glossaryPairKey) to its
+// term substitutions, e.g. {"EN->ZH": {"Kubernetes": "K8s"}}.
+type Glossary map[string]map[string]string
+
+// glossaryPairKey normalizes a source/target language pair into the key
+// format used in the glossary file.
+func glossaryPairKey(sourceLang, targetLang string) string {
+	return strings.ToUpper(sourceLang) + "->" + strings.ToUpper(targetLang)
+}
+
+// loadGlossary reads a glossary YAML file. A missing file is treated as an
+// empty glossary so -glossary can be left pointing at the default path even
+// when the user hasn't created one yet.
+func loadGlossary(path string) (Glossary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Glossary{}, nil
+		}
+		return nil, fmt.Errorf("failed to read glossary file %s: %w", path, err)
+	}
+
+	g := Glossary{}
+	if err := yaml.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse glossary file %s: %w", path, err)
+	}
+	return g, nil
+}
+
+// saveGlossary writes the glossary back to path as YAML.
+func saveGlossary(path string, g Glossary) error {
+	data, err := yaml.Marshal(g)
+	if err != nil {
+		return fmt.Errorf("failed to marshal glossary: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write glossary file %s: %w", path, err)
+	}
+	return nil
+}
+
+// glossaryTermPattern builds a single regex matching any of terms' keys,
+// longest term first so an entry that is itself a substring of another
+// (e.g. "go" vs. "golang") can't shadow the longer match or split it apart.
+// A combined, deterministic regex also avoids the non-determinism of
+// looping ReplaceAll over a map in random iteration order.
+//
+// The pattern deliberately has no \b word-boundary assertions: Go's RE2 \b
+// only understands ASCII word runes, so it would silently never match a
+// glossary term written in CJK or any other non-Latin script. Boundary
+// checking is instead done in applyGlossary via hasWordBoundaries, which is
+// Unicode-aware.
+func glossaryTermPattern(terms map[string]string) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(terms))
+	for term := range terms {
+		keys = append(keys, term)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+
+	escaped := make([]string, len(keys))
+	for i, k := range keys {
+		escaped[i] = regexp.QuoteMeta(k)
+	}
+	return regexp.MustCompile(`(?:` + strings.Join(escaped, "|") + `)`)
+}
+
+// isASCIIWordRune reports whether r is an ASCII letter, digit, or
+// underscore — the character class regexp's \b draws its boundary from.
+//
+// hasWordBoundaries only rejects a match when *both* sides are ASCII word
+// runes. CJK (and most other non-Latin scripts) have no spaces between
+// "words" at all, so unicode.IsLetter-based boundary checking would reject
+// essentially every real-world CJK glossary term; restricting the check to
+// the ASCII case still fixes the original problem (an entry like "go"
+// shadowing "golang") without breaking non-Latin scripts.
+func isASCIIWordRune(r rune) bool {
+	return r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// hasWordBoundaries reports whether text[start:end] is a valid glossary
+// match boundary: it's rejected only when an adjacent character and the
+// match's own edge character are both ASCII word runes (e.g. "go" inside
+// "golang"). Non-ASCII-adjacent matches, including CJK source terms, are
+// always considered bounded.
+func hasWordBoundaries(text string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		matchStart, _ := utf8.DecodeRuneInString(text[start:])
+		if isASCIIWordRune(before) && isASCIIWordRune(matchStart) {
+			return false
+		}
+	}
+	if end < len(text) {
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		matchEnd, _ := utf8.DecodeLastRuneInString(text[:end])
+		if isASCIIWordRune(after) && isASCIIWordRune(matchEnd) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyGlossary wraps every occurrence of a glossary source term for the
+// given language pair in a sentinel ignore-tag so the upstream API leaves it
+// untranslated.
+func applyGlossary(text, sourceLang, targetLang string, g Glossary) string {
+	terms := g[glossaryPairKey(sourceLang, targetLang)]
+	pattern := glossaryTermPattern(terms)
+	if pattern == nil {
+		return text
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range pattern.FindAllStringIndex(text, -1) {
+		start, end := m[0], m[1]
+		if start < last || !hasWordBoundaries(text, start, end) {
+			continue
+		}
+		sb.WriteString(text[last:start])
+		sb.WriteString(wrapGlossaryTerm(text[start:end]))
+		last = end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// glossarySentinelPattern matches a single sentinel-wrapped term, capturing
+// its original (untranslated) content.
+var glossarySentinelPattern = regexp.MustCompile(`<` + glossarySentinelTag + `>(.*?)</` + glossarySentinelTag + `>`)
+
+// substituteGlossary replaces sentinel-wrapped glossary terms in translated
+// text with their target-language equivalent.
+func substituteGlossary(text, sourceLang, targetLang string, g Glossary) string {
+	terms := g[glossaryPairKey(sourceLang, targetLang)]
+	if len(terms) == 0 {
+		return text
+	}
+	return glossarySentinelPattern.ReplaceAllStringFunc(text, func(match string) string {
+		term := glossarySentinelPattern.FindStringSubmatch(match)[1]
+		if translation, ok := terms[term]; ok {
+			return translation
+		}
+		return match
+	})
+}
+
+func wrapGlossaryTerm(term string) string {
+	return fmt.Sprintf("<%s>%s</%s>", glossarySentinelTag, term, glossarySentinelTag)
+}
+
+// runGlossaryCommand implements the `deeplx-cli glossary add/list/remove`
+// subcommands against the glossary file at path.
+func runGlossaryCommand(path string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: deeplx-cli glossary <add|list|remove> ...")
+	}
+
+	g, err := loadGlossary(path)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) != 4 {
+			return fmt.Errorf("usage: deeplx-cli glossary add <SOURCE->TARGET> <term> <translation>")
+		}
+		pair, term, translation := args[1], args[2], args[3]
+		if g[pair] == nil {
+			g[pair] = map[string]string{}
+		}
+		g[pair][term] = translation
+		if err := saveGlossary(path, g); err != nil {
+			return err
+		}
+		fmt.Printf("added %s: %s -> %s\n", pair, term, translation)
+
+	case "list":
+		pairs := make([]string, 0, len(g))
+		for pair := range g {
+			pairs = append(pairs, pair)
+		}
+		sort.Strings(pairs)
+		for _, pair := range pairs {
+			terms := make([]string, 0, len(g[pair]))
+			for term := range g[pair] {
+				terms = append(terms, term)
+			}
+			sort.Strings(terms)
+			for _, term := range terms {
+				fmt.Printf("%s: %s -> %s\n", pair, term, g[pair][term])
+			}
+		}
+
+	case "remove":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: deeplx-cli glossary remove <SOURCE->TARGET> <term>")
+		}
+		pair, term := args[1], args[2]
+		delete(g[pair], term)
+		if err := saveGlossary(path, g); err != nil {
+			return err
+		}
+		fmt.Printf("removed %s: %s\n", pair, term)
+
+	default:
+		return fmt.Errorf("unknown glossary subcommand: %s", args[0])
+	}
+
+	return nil
+}
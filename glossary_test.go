@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestApplyAndSubstituteGlossary(t *testing.T) {
+	g := Glossary{
+		"EN->ZH": {"Kubernetes": "K8s"},
+	}
+
+	wrapped := applyGlossary("I love Kubernetes", "EN", "ZH", g)
+	if wrapped != "I love <deeplx-term>Kubernetes</deeplx-term>" {
+		t.Errorf("unexpected wrapped text: %s", wrapped)
+	}
+
+	translated := "我喜欢 <deeplx-term>Kubernetes</deeplx-term>"
+	result := substituteGlossary(translated, "EN", "ZH", g)
+	if result != "我喜欢 K8s" {
+		t.Errorf("expected '我喜欢 K8s', got '%s'", result)
+	}
+}
+
+func TestApplyGlossaryNoMatchForOtherPair(t *testing.T) {
+	g := Glossary{"EN->ZH": {"Kubernetes": "K8s"}}
+	text := applyGlossary("I love Kubernetes", "EN", "FR", g)
+	if text != "I love Kubernetes" {
+		t.Errorf("expected text unchanged for unconfigured pair, got '%s'", text)
+	}
+}
+
+func TestApplyGlossaryPrefersLongestOverlappingTerm(t *testing.T) {
+	g := Glossary{"EN->ZH": {"go": "围棋", "golang": "Bar"}}
+
+	wrapped := applyGlossary("I love golang", "EN", "ZH", g)
+	want := "I love <deeplx-term>golang</deeplx-term>"
+	if wrapped != want {
+		t.Errorf("expected %q, got %q", want, wrapped)
+	}
+
+	result := substituteGlossary(wrapped, "EN", "ZH", g)
+	want = "I love Bar"
+	if result != want {
+		t.Errorf("expected %q, got %q", want, result)
+	}
+}
+
+func TestApplyGlossaryMatchesNonLatinSourceTerm(t *testing.T) {
+	g := Glossary{"ZH->EN": {"向量数据库": "vector database"}}
+
+	wrapped := applyGlossary("我们使用向量数据库来存储嵌入", "ZH", "EN", g)
+	want := "我们使用<deeplx-term>向量数据库</deeplx-term>来存储嵌入"
+	if wrapped != want {
+		t.Errorf("expected %q, got %q", want, wrapped)
+	}
+
+	translated := "We use <deeplx-term>向量数据库</deeplx-term> to store embeddings"
+	result := substituteGlossary(translated, "ZH", "EN", g)
+	want = "We use vector database to store embeddings"
+	if result != want {
+		t.Errorf("expected %q, got %q", want, result)
+	}
+}
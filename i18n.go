@@ -0,0 +1,384 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const i18nPlaceholderTag = "deeplx-placeholder"
+
+// i18nStateFileSuffix names the sidecar file that tracks, per target locale
+// file, the hash of the reference value each key was last translated from.
+// It lives next to the target file rather than inside it so the locale file
+// itself stays free of tool-specific metadata.
+const i18nStateFileSuffix = ".deeplx-i18n-state.yml"
+
+// i18nManifest describes a reference locale file and the target locales
+// that should be kept in sync with it.
+type i18nManifest struct {
+	Reference struct {
+		Path       string `yaml:"path"`
+		SourceLang string `yaml:"source_lang"`
+	} `yaml:"reference"`
+	Targets []i18nTarget `yaml:"targets"`
+}
+
+// i18nTarget is one output locale: the DeepL target language and the file
+// it should be written to.
+type i18nTarget struct {
+	Lang string `yaml:"lang"`
+	Path string `yaml:"path"`
+}
+
+// loadI18nManifest reads the YAML manifest describing an i18n sync job.
+func loadI18nManifest(path string) (*i18nManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+	m := &i18nManifest{}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// i18nState records, for one target locale file, the hash of the reference
+// value each key was translated from the last time it was synced. It is the
+// mechanism used to decide whether a previously-translated key is stale.
+type i18nState struct {
+	Hashes map[string]string `yaml:"hashes"`
+}
+
+// i18nStatePath returns the sidecar state file path for a target locale file.
+func i18nStatePath(targetPath string) string {
+	return targetPath + i18nStateFileSuffix
+}
+
+// loadI18nState reads the sidecar state file for a target locale file. A
+// missing file is treated as empty state (e.g. the target has never been
+// synced before).
+func loadI18nState(path string) (*i18nState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &i18nState{Hashes: map[string]string{}}, nil
+		}
+		return nil, fmt.Errorf("failed to read i18n state file %s: %w", path, err)
+	}
+	state := &i18nState{}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse i18n state file %s: %w", path, err)
+	}
+	if state.Hashes == nil {
+		state.Hashes = map[string]string{}
+	}
+	return state, nil
+}
+
+// saveI18nState writes the sidecar state file for a target locale file.
+func saveI18nState(path string, state *i18nState) error {
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal i18n state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write i18n state file %s: %w", path, err)
+	}
+	return nil
+}
+
+// sourceHash hashes a reference string value so it can be compared cheaply
+// against the hash recorded the last time that key was translated.
+func sourceHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// localeDocument is a locale resource file (JSON/YAML/TOML), abstracted down
+// to an ordered set of dot-separated leaf string keys so the i18n sync can
+// walk and update them independently of format.
+//
+// Only the YAML implementation preserves comments and original key order on
+// save, since it operates on a yaml.v3 node tree; JSON and TOML round-trip
+// through a plain map and so get re-serialized with keys in sorted order.
+type localeDocument interface {
+	Keys() []string
+	Get(key string) (string, bool)
+	Set(key, value string)
+	Save(path string) error
+}
+
+// loadLocaleDocument loads path (or starts a new empty document of the same
+// format if it doesn't exist yet) and returns it as a localeDocument.
+func loadLocaleDocument(path string) (localeDocument, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return loadYAMLDocument(path)
+	case ".toml":
+		return loadTOMLDocument(path)
+	default:
+		return loadJSONDocument(path)
+	}
+}
+
+// --- YAML, via yaml.v3 so comments and key order survive the round trip ---
+
+type yamlDocument struct {
+	root *yamlv3.Node
+}
+
+func loadYAMLDocument(path string) (*yamlDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			doc := &yamlv3.Node{Kind: yamlv3.DocumentNode}
+			mapping := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+			doc.Content = []*yamlv3.Node{mapping}
+			return &yamlDocument{root: doc}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		root.Kind = yamlv3.DocumentNode
+		root.Content = []*yamlv3.Node{{Kind: yamlv3.MappingNode, Tag: "!!map"}}
+	}
+	return &yamlDocument{root: &root}, nil
+}
+
+func (d *yamlDocument) mapping() *yamlv3.Node {
+	return d.root.Content[0]
+}
+
+// walkYAML recursively lists "a.b.c"-style dot paths for every non-mapping
+// leaf under node (scalars of any type and sequences alike), which is
+// assumed to be a mapping node.
+func walkYAML(prefix string, node *yamlv3.Node, out *[]string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		value := node.Content[i+1]
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if value.Kind == yamlv3.MappingNode {
+			walkYAML(path, value, out)
+		} else {
+			*out = append(*out, path)
+		}
+	}
+}
+
+func (d *yamlDocument) Keys() []string {
+	var keys []string
+	walkYAML("", d.mapping(), &keys)
+	return keys
+}
+
+// findYAML locates the scalar value node for a dot-path, optionally creating
+// intermediate mapping nodes (and the leaf itself) along the way.
+func findYAML(node *yamlv3.Node, parts []string, create bool) *yamlv3.Node {
+	key := parts[0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			if len(parts) == 1 {
+				return node.Content[i+1]
+			}
+			return findYAML(node.Content[i+1], parts[1:], create)
+		}
+	}
+	if !create {
+		return nil
+	}
+
+	keyNode := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}
+	var valueNode *yamlv3.Node
+	if len(parts) == 1 {
+		valueNode = &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str"}
+	} else {
+		valueNode = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+	}
+	node.Content = append(node.Content, keyNode, valueNode)
+	if len(parts) == 1 {
+		return valueNode
+	}
+	return findYAML(valueNode, parts[1:], create)
+}
+
+func (d *yamlDocument) Get(key string) (string, bool) {
+	n := findYAML(d.mapping(), strings.Split(key, "."), false)
+	if n == nil || n.Kind != yamlv3.ScalarNode {
+		return "", false
+	}
+	return n.Value, true
+}
+
+func (d *yamlDocument) Set(key, value string) {
+	n := findYAML(d.mapping(), strings.Split(key, "."), true)
+	n.Kind = yamlv3.ScalarNode
+	n.Tag = "!!str"
+	n.Value = value
+}
+
+func (d *yamlDocument) Save(path string) error {
+	data, err := yamlv3.Marshal(d.root)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// --- JSON and TOML, via plain nested maps (order not preserved on save) ---
+
+type mapDocument struct {
+	data   map[string]interface{}
+	format string // "json" or "toml"
+}
+
+func loadJSONDocument(path string) (*mapDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &mapDocument{data: map[string]interface{}{}, format: "json"}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &mapDocument{data: m, format: "json"}, nil
+}
+
+func loadTOMLDocument(path string) (*mapDocument, error) {
+	m := map[string]interface{}{}
+	if _, err := toml.DecodeFile(path, &m); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	return &mapDocument{data: m, format: "toml"}, nil
+}
+
+func walkMap(prefix string, m map[string]interface{}, out *[]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := m[key].(map[string]interface{}); ok {
+			walkMap(path, nested, out)
+		} else {
+			*out = append(*out, path)
+		}
+	}
+}
+
+func (d *mapDocument) Keys() []string {
+	var keys []string
+	walkMap("", d.data, &keys)
+	return keys
+}
+
+func (d *mapDocument) Get(key string) (string, bool) {
+	parts := strings.Split(key, ".")
+	m := d.data
+	for i, part := range parts {
+		if i == len(parts)-1 {
+			s, ok := m[part].(string)
+			return s, ok
+		}
+		nested, ok := m[part].(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		m = nested
+	}
+	return "", false
+}
+
+func (d *mapDocument) Set(key, value string) {
+	parts := strings.Split(key, ".")
+	m := d.data
+	for i, part := range parts {
+		if i == len(parts)-1 {
+			m[part] = value
+			return
+		}
+		nested, ok := m[part].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			m[part] = nested
+		}
+		m = nested
+	}
+}
+
+func (d *mapDocument) Save(path string) error {
+	switch d.format {
+	case "toml":
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+		if err := toml.NewEncoder(f).Encode(d.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	default:
+		data, err := json.MarshalIndent(d.data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+// --- placeholder protection ---
+
+var i18nPlaceholderPattern = regexp.MustCompile(`\{[^{}]+\}|%[sdv]`)
+
+// wrapPlaceholders wraps `{name}`-style and `%s`-style placeholders in a
+// sentinel ignore-tag so the upstream API passes them through untranslated.
+func wrapPlaceholders(text string) string {
+	return i18nPlaceholderPattern.ReplaceAllStringFunc(text, func(m string) string {
+		return fmt.Sprintf("<%s>%s</%s>", i18nPlaceholderTag, m, i18nPlaceholderTag)
+	})
+}
+
+var i18nPlaceholderTagPattern = regexp.MustCompile(`</?` + i18nPlaceholderTag + `>`)
+
+// stripPlaceholderTags removes the sentinel ignore-tags left in translated
+// text, restoring the bare placeholder.
+func stripPlaceholderTags(text string) string {
+	return i18nPlaceholderTagPattern.ReplaceAllString(text, "")
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ubuygold/deeplx-cli/internal/pool"
+)
+
+func TestWrapAndStripPlaceholders(t *testing.T) {
+	wrapped := wrapPlaceholders("Hello {name}, you have %d messages")
+	want := "Hello <deeplx-placeholder>{name}</deeplx-placeholder>, you have <deeplx-placeholder>%d</deeplx-placeholder> messages"
+	if wrapped != want {
+		t.Errorf("unexpected wrapped text:\ngot:  %s\nwant: %s", wrapped, want)
+	}
+
+	stripped := stripPlaceholderTags(wrapped)
+	want = "Hello {name}, you have %d messages"
+	if stripped != want {
+		t.Errorf("unexpected stripped text:\ngot:  %s\nwant: %s", stripped, want)
+	}
+}
+
+func TestYAMLDocumentRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "en.yml")
+
+	doc, err := loadYAMLDocument(path)
+	if err != nil {
+		t.Fatalf("loadYAMLDocument failed: %v", err)
+	}
+	doc.Set("greeting.hello", "Hello")
+	if err := doc.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded, err := loadYAMLDocument(path)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	value, ok := reloaded.Get("greeting.hello")
+	if !ok || value != "Hello" {
+		t.Errorf("expected 'Hello', got %q (ok=%v)", value, ok)
+	}
+}
+
+func TestJSONDocumentSetAndGet(t *testing.T) {
+	doc, err := loadJSONDocument(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("loadJSONDocument failed: %v", err)
+	}
+	doc.Set("errors.notFound", "Not found")
+	value, ok := doc.Get("errors.notFound")
+	if !ok || value != "Not found" {
+		t.Errorf("expected 'Not found', got %q (ok=%v)", value, ok)
+	}
+}
+
+// upperCasingEchoServer stubs the upstream DeepLX API by uppercasing the
+// request text, so tests can tell translated values apart from untouched ones.
+func upperCasingEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TranslationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		json.NewEncoder(w).Encode(TranslationResponse{Code: 200, Data: strings.ToUpper(req.Text)})
+	}))
+}
+
+func writeManifest(t *testing.T, manifestPath, refPath, targetPath, targetLang string) {
+	t.Helper()
+	content := "reference:\n  path: " + refPath + "\n  source_lang: EN\ntargets:\n  - lang: " + targetLang + "\n    path: " + targetPath + "\n"
+	if err := os.WriteFile(manifestPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+}
+
+func TestRunTranslateI18nOnlyMissing(t *testing.T) {
+	server := upperCasingEchoServer(t)
+	defer server.Close()
+	p := pool.New([]pool.Endpoint{{URL: server.URL, Weight: 1}}, pool.StrategyFailover)
+
+	dir := t.TempDir()
+	refPath := filepath.Join(dir, "en.json")
+	targetPath := filepath.Join(dir, "zh.json")
+	manifestPath := filepath.Join(dir, "manifest.yml")
+
+	os.WriteFile(refPath, []byte(`{"greeting": "hello", "farewell": "bye"}`), 0644)
+	os.WriteFile(targetPath, []byte(`{"greeting": "existing-hi"}`), 0644)
+	writeManifest(t, manifestPath, refPath, targetPath, "ZH")
+
+	opts := i18nOptions{manifestPath: manifestPath, onlyMissing: true, overwrite: true}
+	if err := runTranslateI18n(opts, p, nil); err != nil {
+		t.Fatalf("runTranslateI18n failed: %v", err)
+	}
+
+	doc, err := loadJSONDocument(targetPath)
+	if err != nil {
+		t.Fatalf("failed to reload target: %v", err)
+	}
+	if v, _ := doc.Get("greeting"); v != "existing-hi" {
+		t.Errorf("expected existing 'greeting' untouched by -only-missing, got %q", v)
+	}
+	if v, _ := doc.Get("farewell"); v != "BYE" {
+		t.Errorf("expected missing 'farewell' translated to 'BYE', got %q", v)
+	}
+}
+
+func TestRunTranslateI18nOverwriteOnlyRetranslatesStaleKeys(t *testing.T) {
+	server := upperCasingEchoServer(t)
+	defer server.Close()
+	p := pool.New([]pool.Endpoint{{URL: server.URL, Weight: 1}}, pool.StrategyFailover)
+
+	dir := t.TempDir()
+	refPath := filepath.Join(dir, "en.json")
+	targetPath := filepath.Join(dir, "zh.json")
+	manifestPath := filepath.Join(dir, "manifest.yml")
+
+	os.WriteFile(refPath, []byte(`{"greeting": "hello"}`), 0644)
+	os.WriteFile(targetPath, []byte(`{"greeting": "HI-ALREADY-CURRENT"}`), 0644)
+	// Pre-seed the state file as if "greeting" was already synced from "hello".
+	if err := saveI18nState(i18nStatePath(targetPath), &i18nState{Hashes: map[string]string{"greeting": sourceHash("hello")}}); err != nil {
+		t.Fatalf("failed to seed state: %v", err)
+	}
+	writeManifest(t, manifestPath, refPath, targetPath, "ZH")
+
+	opts := i18nOptions{manifestPath: manifestPath, overwrite: true}
+	if err := runTranslateI18n(opts, p, nil); err != nil {
+		t.Fatalf("runTranslateI18n failed: %v", err)
+	}
+
+	doc, err := loadJSONDocument(targetPath)
+	if err != nil {
+		t.Fatalf("failed to reload target: %v", err)
+	}
+	if v, _ := doc.Get("greeting"); v != "HI-ALREADY-CURRENT" {
+		t.Errorf("expected up-to-date 'greeting' left alone, got %q", v)
+	}
+
+	// Now the reference value changes, so the key becomes stale and should
+	// be retranslated on the next -overwrite run.
+	os.WriteFile(refPath, []byte(`{"greeting": "hello there"}`), 0644)
+	if err := runTranslateI18n(opts, p, nil); err != nil {
+		t.Fatalf("second runTranslateI18n failed: %v", err)
+	}
+	doc, err = loadJSONDocument(targetPath)
+	if err != nil {
+		t.Fatalf("failed to reload target: %v", err)
+	}
+	if v, _ := doc.Get("greeting"); v != "HELLO THERE" {
+		t.Errorf("expected stale 'greeting' retranslated to 'HELLO THERE', got %q", v)
+	}
+}
+
+func TestRunTranslateI18nDryRunWritesNothing(t *testing.T) {
+	server := upperCasingEchoServer(t)
+	defer server.Close()
+	p := pool.New([]pool.Endpoint{{URL: server.URL, Weight: 1}}, pool.StrategyFailover)
+
+	dir := t.TempDir()
+	refPath := filepath.Join(dir, "en.json")
+	targetPath := filepath.Join(dir, "zh.json")
+	manifestPath := filepath.Join(dir, "manifest.yml")
+
+	os.WriteFile(refPath, []byte(`{"greeting": "hello"}`), 0644)
+	writeManifest(t, manifestPath, refPath, targetPath, "ZH")
+
+	opts := i18nOptions{manifestPath: manifestPath, dryRun: true}
+	if err := runTranslateI18n(opts, p, nil); err != nil {
+		t.Fatalf("runTranslateI18n failed: %v", err)
+	}
+
+	if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
+		t.Errorf("expected -dry-run to not create the target file, stat err=%v", err)
+	}
+	if _, err := os.Stat(i18nStatePath(targetPath)); !os.IsNotExist(err) {
+		t.Errorf("expected -dry-run to not create a state file, stat err=%v", err)
+	}
+}
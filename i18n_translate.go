@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ubuygold/deeplx-cli/internal/cache"
+	"github.com/ubuygold/deeplx-cli/internal/pool"
+)
+
+// i18nOptions configures a single translate-i18n run.
+type i18nOptions struct {
+	manifestPath string
+	onlyMissing  bool
+	overwrite    bool
+	dryRun       bool
+}
+
+// keyStatus reports whether a reference key needs (re)translation into a
+// target locale, based on the sidecar i18nState recorded for that key.
+func keyStatus(targetDoc localeDocument, state *i18nState, key, refValue string, opts i18nOptions) (needsTranslation bool) {
+	_, exists := targetDoc.Get(key)
+	if !exists {
+		return true
+	}
+	if opts.onlyMissing {
+		return false
+	}
+	if !opts.overwrite {
+		return false
+	}
+	// The key exists and -overwrite is set: only retranslate if the
+	// reference value has changed since the last time this key was synced.
+	return state.Hashes[key] != sourceHash(refValue)
+}
+
+// runTranslateI18n walks the reference locale file named in the manifest
+// and, for each target locale, fills in any string that is missing (or, with
+// opts.overwrite, stale) by translating the reference value.
+func runTranslateI18n(opts i18nOptions, p *pool.Pool, c *cache.Store) error {
+	manifest, err := loadI18nManifest(opts.manifestPath)
+	if err != nil {
+		return err
+	}
+
+	reference, err := loadLocaleDocument(manifest.Reference.Path)
+	if err != nil {
+		return fmt.Errorf("failed to load reference locale %s: %w", manifest.Reference.Path, err)
+	}
+	sourceLang := manifest.Reference.SourceLang
+	if sourceLang == "" {
+		sourceLang = "auto"
+	}
+
+	for _, target := range manifest.Targets {
+		if err := syncLocale(reference, target, sourceLang, opts, p, c); err != nil {
+			return fmt.Errorf("failed to sync target %s: %w", target.Path, err)
+		}
+	}
+	return nil
+}
+
+// syncLocale brings a single target locale file in line with reference. A
+// key is (re)translated if it's missing from the target, or if -overwrite is
+// set and the reference value has changed since the last sync (tracked via
+// a sidecar i18nState file next to the target).
+func syncLocale(reference localeDocument, target i18nTarget, sourceLang string, opts i18nOptions, p *pool.Pool, c *cache.Store) error {
+	targetDoc, err := loadLocaleDocument(target.Path)
+	if err != nil {
+		return err
+	}
+	statePath := i18nStatePath(target.Path)
+	state, err := loadI18nState(statePath)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, key := range reference.Keys() {
+		refValue, ok := reference.Get(key)
+		if !ok {
+			continue
+		}
+		if !keyStatus(targetDoc, state, key, refValue, opts) {
+			continue
+		}
+
+		translated, err := translateRequest(TranslationRequest{
+			Text:        wrapPlaceholders(refValue),
+			SourceLang:  sourceLang,
+			TargetLang:  target.Lang,
+			TagHandling: "xml",
+			IgnoreTags:  []string{i18nPlaceholderTag},
+		}, p, c)
+		if err != nil {
+			return fmt.Errorf("failed to translate key %s: %w", key, err)
+		}
+		translated = stripPlaceholderTags(translated)
+
+		if opts.dryRun {
+			fmt.Printf("[%s] %s: %q -> %q\n", target.Lang, key, refValue, translated)
+			continue
+		}
+
+		targetDoc.Set(key, translated)
+		state.Hashes[key] = sourceHash(refValue)
+		changed = true
+	}
+
+	if changed && !opts.dryRun {
+		if err := targetDoc.Save(target.Path); err != nil {
+			return err
+		}
+		if err := saveI18nState(statePath, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseI18nArgs parses the flags for the `translate-i18n` subcommand.
+func parseI18nArgs(args []string) (i18nOptions, error) {
+	fs := flag.NewFlagSet("translate-i18n", flag.ContinueOnError)
+	opts := i18nOptions{}
+	fs.StringVar(&opts.manifestPath, "manifest", "", "Path to the i18n sync manifest YAML file.")
+	fs.BoolVar(&opts.onlyMissing, "only-missing", false, "Only translate keys missing from the target, even with -overwrite.")
+	fs.BoolVar(&opts.overwrite, "overwrite", false, "Re-translate keys whose reference value has changed since the last sync (tracked per-target in a sidecar .deeplx-i18n-state.yml file), in addition to missing keys.")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "Print what would change without writing any files.")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if opts.manifestPath == "" {
+		return opts, fmt.Errorf("usage: deeplx-cli translate-i18n -manifest <path> [--only-missing] [--overwrite] [--dry-run]")
+	}
+	return opts, nil
+}
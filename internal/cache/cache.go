@@ -0,0 +1,157 @@
+// Package cache implements a small persistent translation cache keyed by
+// the normalized (text, source_lang, target_lang, endpoint) tuple, used to
+// avoid re-translating the same segment on repeated invocations.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultFileName = "cache.json"
+
+// entry is a single cached translation along with its insertion time, used
+// both for TTL expiry and for max-size eviction (oldest entries first).
+type entry struct {
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Stats summarizes the current state of a Store.
+type Stats struct {
+	Path    string
+	Entries int
+}
+
+// Store is an on-disk, JSON-backed translation cache.
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	maxSize int
+	entries map[string]entry
+}
+
+// Open loads (or creates) a cache file at dir/cache.json. A ttl of zero
+// means entries never expire; a maxSize of zero means no eviction limit.
+func Open(dir string, ttl time.Duration, maxSize int) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+
+	s := &Store{
+		path:    filepath.Join(dir, defaultFileName),
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]entry),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read cache file %s: %w", s.path, err)
+	}
+
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse cache file %s: %w", s.path, err)
+	}
+	return s, nil
+}
+
+// Key computes the cache key for a (text, source_lang, target_lang,
+// endpoint) tuple, normalizing case and surrounding whitespace so equivalent
+// requests share an entry.
+func Key(text, sourceLang, targetLang, endpoint string) string {
+	normalized := strings.ToLower(strings.TrimSpace(text)) + "|" +
+		strings.ToLower(strings.TrimSpace(sourceLang)) + "|" +
+		strings.ToLower(strings.TrimSpace(targetLang)) + "|" +
+		strings.TrimSpace(endpoint)
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached value for key, if present and not expired.
+func (s *Store) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return "", false
+	}
+	if s.ttl > 0 && time.Since(e.CreatedAt) > s.ttl {
+		delete(s.entries, key)
+		return "", false
+	}
+	return e.Value, true
+}
+
+// Set stores value under key and persists the cache to disk, evicting the
+// oldest entries first if maxSize is exceeded.
+func (s *Store) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = entry{Value: value, CreatedAt: time.Now()}
+	s.evictLocked()
+	return s.saveLocked()
+}
+
+// evictLocked removes the oldest entries until the store is within maxSize.
+// s.mu must be held.
+func (s *Store) evictLocked() {
+	if s.maxSize <= 0 || len(s.entries) <= s.maxSize {
+		return
+	}
+
+	keys := make([]string, 0, len(s.entries))
+	for k := range s.entries {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return s.entries[keys[i]].CreatedAt.Before(s.entries[keys[j]].CreatedAt)
+	})
+
+	for _, k := range keys[:len(s.entries)-s.maxSize] {
+		delete(s.entries, k)
+	}
+}
+
+// saveLocked writes the current entry set to disk. s.mu must be held.
+func (s *Store) saveLocked() error {
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// Clear removes every entry from the cache, in memory and on disk.
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]entry)
+	return s.saveLocked()
+}
+
+// Stats reports the cache's current size and on-disk location.
+func (s *Store) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Stats{Path: s.path, Entries: len(s.entries)}
+}
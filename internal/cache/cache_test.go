@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetAndGet(t *testing.T) {
+	s, err := Open(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	key := Key("hello", "auto", "ZH", "http://endpoint")
+	if err := s.Set(key, "你好"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	value, ok := s.Get(key)
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if value != "你好" {
+		t.Errorf("expected '你好', got '%s'", value)
+	}
+}
+
+func TestGetExpiresEntriesPastTTL(t *testing.T) {
+	s, err := Open(t.TempDir(), time.Millisecond, 0)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	key := Key("hello", "auto", "ZH", "http://endpoint")
+	_ = s.Set(key, "你好")
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := s.Get(key); ok {
+		t.Error("expected cache miss after TTL expiry")
+	}
+}
+
+func TestSetEvictsOldestWhenOverMaxSize(t *testing.T) {
+	s, err := Open(t.TempDir(), 0, 1)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	oldKey := Key("old", "auto", "ZH", "http://endpoint")
+	_ = s.Set(oldKey, "old-value")
+	time.Sleep(time.Millisecond)
+	newKey := Key("new", "auto", "ZH", "http://endpoint")
+	_ = s.Set(newKey, "new-value")
+
+	if _, ok := s.Get(oldKey); ok {
+		t.Error("expected oldest entry to be evicted")
+	}
+	if _, ok := s.Get(newKey); !ok {
+		t.Error("expected newest entry to remain")
+	}
+}
+
+func TestClearRemovesAllEntries(t *testing.T) {
+	s, err := Open(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	key := Key("hello", "auto", "ZH", "http://endpoint")
+	_ = s.Set(key, "你好")
+
+	if err := s.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if stats := s.Stats(); stats.Entries != 0 {
+		t.Errorf("expected 0 entries after Clear, got %d", stats.Entries)
+	}
+}
@@ -0,0 +1,212 @@
+// Package pool implements a failover pool of DeepLX-compatible endpoints,
+// used to spread translation requests across multiple self-hosted mirrors.
+package pool
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Strategy selects how the pool picks the next endpoint to try.
+type Strategy string
+
+const (
+	StrategyRoundRobin Strategy = "roundrobin"
+	StrategyRandom     Strategy = "random"
+	StrategyFailover   Strategy = "failover"
+)
+
+const (
+	defaultTimeout              = 10 * time.Second
+	maxFailuresBeforeQuarantine = 3
+	quarantineDuration          = 30 * time.Second
+	maxRetries                  = 3
+	initialBackoff              = 200 * time.Millisecond
+)
+
+// Endpoint describes a single DeepLX-compatible translation backend.
+type Endpoint struct {
+	URL     string
+	Weight  int
+	Timeout time.Duration
+	Token   string
+}
+
+// Result is the outcome of a single HTTP call made against an Endpoint.
+type Result struct {
+	StatusCode int
+	Body       []byte
+}
+
+// DoFunc performs one HTTP call against ep and reports the raw result.
+type DoFunc func(ep Endpoint) (Result, error)
+
+type endpointState struct {
+	endpoint    Endpoint
+	failures    int
+	quarantined time.Time // zero value means not quarantined
+	rrCount     int       // not read; pickWeighted keeps its position in Pool.next
+}
+
+// Pool is a failover pool of endpoints that retries failed requests against
+// other members of the pool with exponential backoff.
+type Pool struct {
+	mu        sync.Mutex
+	endpoints []*endpointState
+	strategy  Strategy
+	next      int // round-robin cursor
+}
+
+// New builds a Pool from endpoints using the given selection strategy.
+// Endpoints with a non-positive weight default to a weight of 1.
+func New(endpoints []Endpoint, strategy Strategy) *Pool {
+	states := make([]*endpointState, 0, len(endpoints))
+	for _, e := range endpoints {
+		if e.Weight <= 0 {
+			e.Weight = 1
+		}
+		if e.Timeout <= 0 {
+			e.Timeout = defaultTimeout
+		}
+		states = append(states, &endpointState{endpoint: e})
+	}
+	return &Pool{endpoints: states, strategy: strategy}
+}
+
+// Signature returns a stable identifier for the pool's endpoint set, suitable
+// for use as the "endpoint" dimension of an external cache key.
+func (p *Pool) Signature() string {
+	urls := make([]string, len(p.endpoints))
+	for i, s := range p.endpoints {
+		urls[i] = s.endpoint.URL
+	}
+	sort.Strings(urls)
+	return strings.Join(urls, ",")
+}
+
+// Do selects endpoints from the pool (per the configured strategy) and
+// invokes fn against each in turn, retrying on transport errors or
+// non-200/429/5xx responses with exponential backoff until one succeeds or
+// the pool is exhausted.
+func (p *Pool) Do(fn DoFunc) (Result, error) {
+	if len(p.endpoints) == 0 {
+		return Result{}, fmt.Errorf("pool: no endpoints configured")
+	}
+
+	var lastErr error
+	backoff := initialBackoff
+
+	tried := 0
+	maxAttempts := len(p.endpoints) * maxRetries
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		state := p.pick()
+		if state == nil {
+			break
+		}
+		tried++
+
+		result, err := fn(state.endpoint)
+		if err == nil && result.StatusCode == 200 {
+			p.markSuccess(state)
+			return result, nil
+		}
+
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("endpoint %s returned status %d", state.endpoint.URL, result.StatusCode)
+		}
+		p.markFailure(state)
+
+		if attempt+1 >= maxAttempts {
+			// This was the last attempt; no retry follows, so don't sleep.
+			break
+		}
+
+		if p.strategy == StrategyFailover {
+			// Retry without delay on failover. pick() keeps returning the
+			// first healthy endpoint until it is quarantined, and only then
+			// moves on to the next one.
+			continue
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	if tried == 0 {
+		return Result{}, fmt.Errorf("pool: all endpoints are quarantined")
+	}
+	return Result{}, fmt.Errorf("pool: all endpoints failed: %w", lastErr)
+}
+
+// pick returns the next endpoint to try according to the pool's strategy,
+// skipping quarantined endpoints unless every endpoint is quarantined.
+func (p *Pool) pick() *endpointState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	healthy := make([]*endpointState, 0, len(p.endpoints))
+	for _, s := range p.endpoints {
+		if s.quarantined.IsZero() || time.Now().After(s.quarantined.Add(quarantineDuration)) {
+			healthy = append(healthy, s)
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = p.endpoints
+	}
+
+	switch p.strategy {
+	case StrategyRandom:
+		return healthy[rand.Intn(len(healthy))]
+	case StrategyFailover:
+		return healthy[0]
+	default: // weighted round-robin
+		return pickWeighted(healthy, &p.next)
+	}
+}
+
+// pickWeighted implements weighted round-robin selection over the given
+// candidates, advancing the shared cursor: the cursor cycles through
+// 0..totalWeight-1 and each candidate owns a run of Weight consecutive
+// slots, so it is picked Weight times in a row.
+func pickWeighted(candidates []*endpointState, cursor *int) *endpointState {
+	totalWeight := 0
+	for _, c := range candidates {
+		totalWeight += c.endpoint.Weight
+	}
+	if totalWeight == 0 {
+		*cursor = (*cursor + 1) % len(candidates)
+		return candidates[*cursor]
+	}
+
+	*cursor = (*cursor + 1) % totalWeight
+	cum := 0
+	for _, c := range candidates {
+		cum += c.endpoint.Weight
+		if *cursor < cum {
+			return c
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+func (p *Pool) markSuccess(s *endpointState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s.failures = 0
+	s.quarantined = time.Time{}
+}
+
+func (p *Pool) markFailure(s *endpointState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s.failures++
+	if s.failures >= maxFailuresBeforeQuarantine {
+		s.quarantined = time.Now()
+	}
+}
@@ -0,0 +1,72 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDoReturnsFirstSuccess(t *testing.T) {
+	p := New([]Endpoint{{URL: "http://a"}}, StrategyFailover)
+
+	result, err := p.Do(func(ep Endpoint) (Result, error) {
+		return Result{StatusCode: 200, Body: []byte("ok")}, nil
+	})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if string(result.Body) != "ok" {
+		t.Errorf("expected body 'ok', got '%s'", result.Body)
+	}
+}
+
+func TestDoFailsOverToHealthyEndpoint(t *testing.T) {
+	p := New([]Endpoint{{URL: "http://bad"}, {URL: "http://good"}}, StrategyFailover)
+
+	result, err := p.Do(func(ep Endpoint) (Result, error) {
+		if ep.URL == "http://bad" {
+			return Result{StatusCode: 500}, nil
+		}
+		return Result{StatusCode: 200, Body: []byte("from-good")}, nil
+	})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if string(result.Body) != "from-good" {
+		t.Errorf("expected body 'from-good', got '%s'", result.Body)
+	}
+}
+
+func TestDoReturnsErrorWhenAllEndpointsFail(t *testing.T) {
+	p := New([]Endpoint{{URL: "http://a"}}, StrategyFailover)
+
+	_, err := p.Do(func(ep Endpoint) (Result, error) {
+		return Result{StatusCode: 500}, nil
+	})
+	if err == nil {
+		t.Fatal("expected an error when all endpoints fail")
+	}
+}
+
+func TestDoDoesNotSleepAfterFinalAttempt(t *testing.T) {
+	// A single-endpoint round-robin pool retries maxRetries times with
+	// exponential backoff between attempts, but must not sleep after the
+	// final attempt since no further retry follows.
+	p := New([]Endpoint{{URL: "http://a"}}, StrategyRoundRobin)
+
+	start := time.Now()
+	_, err := p.Do(func(ep Endpoint) (Result, error) {
+		return Result{StatusCode: 500}, nil
+	})
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected an error when all endpoints fail")
+	}
+	// maxRetries is 3, so with initialBackoff=200ms there are only 2 sleeps
+	// (200ms + 400ms = 600ms) if the last attempt correctly skips its sleep.
+	// Allow generous headroom above that but well under the ~1.4s it would
+	// take if the doomed final attempt slept too.
+	if elapsed > 900*time.Millisecond {
+		t.Errorf("Do took %v, expected well under 900ms (no sleep after final attempt)", elapsed)
+	}
+}
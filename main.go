@@ -12,14 +12,19 @@ import (
 	"os/user"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
 	"gopkg.in/yaml.v2"
+
+	"github.com/ubuygold/deeplx-cli/internal/cache"
+	"github.com/ubuygold/deeplx-cli/internal/pool"
 )
 
 const (
 	defaultDeepLXAPI = "https://deeplx.vercel.app/translate"
 	configFileName   = ".deeplx-cli.yml"
+	cacheDirName     = "deeplx-cli"
 )
 
 // version will be set by build flags
@@ -27,9 +32,52 @@ var version = "dev"
 
 // Config struct defines the structure of the configuration file
 type Config struct {
-	URL        string `yaml:"url"`
-	SourceLang string `yaml:"source_lang"`
-	TargetLang string `yaml:"target_lang"`
+	// URL accepts either a single endpoint ("url: https://...") or a list
+	// ("url: [https://..., https://...]") for simple multi-endpoint setups.
+	URL        URLList          `yaml:"url"`
+	Endpoints  []EndpointConfig `yaml:"endpoints"`
+	SourceLang string           `yaml:"source_lang"`
+	TargetLang string           `yaml:"target_lang"`
+	AuthToken  string           `yaml:"auth_token"`
+
+	TagHandling        string   `yaml:"tag_handling"`
+	SplittingTags      []string `yaml:"splitting_tags"`
+	NonSplittingTags   []string `yaml:"non_splitting_tags"`
+	IgnoreTags         []string `yaml:"ignore_tags"`
+	PreserveFormatting bool     `yaml:"preserve_formatting"`
+
+	CacheTTL     time.Duration `yaml:"cache_ttl"`
+	CacheMaxSize int           `yaml:"cache_max_size"`
+}
+
+// EndpointConfig describes one member of a multi-endpoint failover pool.
+type EndpointConfig struct {
+	URL     string        `yaml:"url"`
+	Weight  int           `yaml:"weight"`
+	Timeout time.Duration `yaml:"timeout"`
+	Token   string        `yaml:"token"`
+}
+
+// URLList is a Config.URL that can be unmarshalled from either a single YAML
+// scalar or a YAML sequence of strings.
+type URLList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler, accepting both forms of URL.
+func (u *URLList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		if single != "" {
+			*u = URLList{single}
+		}
+		return nil
+	}
+
+	var multi []string
+	if err := unmarshal(&multi); err != nil {
+		return err
+	}
+	*u = URLList(multi)
+	return nil
 }
 
 // TranslationRequest struct defines the JSON structure of the translation request
@@ -37,6 +85,18 @@ type TranslationRequest struct {
 	Text       string `json:"text"`
 	SourceLang string `json:"source_lang"`
 	TargetLang string `json:"target_lang"`
+
+	// TagHandling controls how inline markup is treated by the upstream API.
+	// Supported values are "xml" and "html"; leave empty to disable.
+	TagHandling string `json:"tag_handling,omitempty"`
+	// SplittingTags lists tags on which the engine is allowed to split sentences.
+	SplittingTags []string `json:"splitting_tags,omitempty"`
+	// NonSplittingTags lists tags that must never be used as sentence boundaries.
+	NonSplittingTags []string `json:"non_splitting_tags,omitempty"`
+	// IgnoreTags lists tags whose content is passed through untranslated.
+	IgnoreTags []string `json:"ignore_tags,omitempty"`
+	// PreserveFormatting keeps original formatting such as leading/trailing whitespace.
+	PreserveFormatting bool `json:"preserve_formatting,omitempty"`
 }
 
 // TranslationResponse struct defines the JSON structure of the translation response
@@ -47,6 +107,38 @@ type TranslationResponse struct {
 	Data    string `json:"data"`
 }
 
+// loadConfigOrDefault loads the config at configPath, writing out a default
+// config file first if none exists yet.
+func loadConfigOrDefault(configPath string) (*Config, error) {
+	cfg := &Config{}
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		log.Printf("Config file %s does not exist, generating default config.", configPath)
+		defaultConfig := &Config{
+			URL:        URLList{defaultDeepLXAPI},
+			SourceLang: "auto",
+			TargetLang: "EN",
+		}
+		yamlData, err := yaml.Marshal(defaultConfig)
+		if err != nil {
+			log.Printf("Warning: Failed to marshal default config: %v", err)
+		} else if err := os.WriteFile(configPath, yamlData, 0644); err != nil {
+			log.Printf("Warning: Failed to write default config file %s: %v", configPath, err)
+		} else {
+			log.Printf("Default config file generated: %s", configPath)
+		}
+		cfg = defaultConfig
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to check config file %s: %w", configPath, err)
+	}
+
+	loadedConfig, err := loadConfig(configPath)
+	if err != nil {
+		log.Printf("Warning: Failed to load or parse config file %s, using default values or command-line arguments: %v", configPath, err)
+		return cfg, nil
+	}
+	return loadedConfig, nil
+}
+
 // loadConfig loads the YAML configuration file from the specified path
 func loadConfig(configPath string) (*Config, error) {
 	config := &Config{}
@@ -62,36 +154,109 @@ func loadConfig(configPath string) (*Config, error) {
 	return config, nil
 }
 
+// buildPool assembles a pool.Pool from the endpoints configured via
+// Config.URL / Config.Endpoints, falling back to defaultDeepLXAPI when none
+// are set.
+func buildPool(cfg *Config, strategy pool.Strategy) *pool.Pool {
+	var endpoints []pool.Endpoint
+	for _, u := range cfg.URL {
+		endpoints = append(endpoints, pool.Endpoint{URL: u, Weight: 1})
+	}
+	for _, e := range cfg.Endpoints {
+		endpoints = append(endpoints, pool.Endpoint{
+			URL:     e.URL,
+			Weight:  e.Weight,
+			Timeout: e.Timeout,
+			Token:   e.Token,
+		})
+	}
+	if len(endpoints) == 0 {
+		endpoints = append(endpoints, pool.Endpoint{URL: defaultDeepLXAPI, Weight: 1})
+	}
+	return pool.New(endpoints, strategy)
+}
+
 // translateText encapsulates the translation logic
-func translateText(text, sourceLang, targetLang, apiURL string) (string, error) {
-	requestBody, err := json.Marshal(TranslationRequest{
+func translateText(text, sourceLang, targetLang string, p *pool.Pool, c *cache.Store) (string, error) {
+	return translateRequest(TranslationRequest{
 		Text:       text,
 		SourceLang: sourceLang,
 		TargetLang: targetLang,
-	})
-	if err != nil {
-		return "", fmt.Errorf("error marshalling request: %w", err)
+	}, p, c)
+}
+
+// translateTextWithGlossary behaves like translateText but first wraps any
+// configured glossary terms so the upstream API leaves them untranslated,
+// then substitutes them with their target-language equivalent afterwards.
+func translateTextWithGlossary(text, sourceLang, targetLang string, p *pool.Pool, c *cache.Store, g Glossary) (string, error) {
+	hasTerms := len(g[glossaryPairKey(sourceLang, targetLang)]) > 0
+	if !hasTerms {
+		return translateText(text, sourceLang, targetLang, p, c)
 	}
 
-	resp, err := http.Post(apiURL, "application/json", bytes.NewBuffer(requestBody))
+	req := TranslationRequest{
+		Text:        applyGlossary(text, sourceLang, targetLang, g),
+		SourceLang:  sourceLang,
+		TargetLang:  targetLang,
+		TagHandling: "xml",
+		IgnoreTags:  []string{glossarySentinelTag},
+	}
+
+	result, err := translateRequest(req, p, c)
 	if err != nil {
-		return "", fmt.Errorf("error sending request to DeepLX API: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
+	return substituteGlossary(result, sourceLang, targetLang, g), nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("DeepLX API returned non-200 status: %d, body: %s", resp.StatusCode, string(bodyBytes))
+// translateRequest sends a fully populated TranslationRequest (including
+// optional tag-handling fields) to the pool's DeepLX endpoints and returns
+// the translation, retrying and failing over per the pool's strategy. If c
+// is non-nil, it is consulted before the request is sent and updated with
+// the result afterwards.
+func translateRequest(req TranslationRequest, p *pool.Pool, c *cache.Store) (string, error) {
+	var cacheKey string
+	if c != nil {
+		cacheKey = cache.Key(req.Text, req.SourceLang, req.TargetLang, p.Signature())
+		if cached, ok := c.Get(cacheKey); ok {
+			return cached, nil
+		}
 	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	requestBody, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("error reading response body: %w", err)
+		return "", fmt.Errorf("error marshalling request: %w", err)
 	}
 
-	var translationResponse TranslationResponse
-	err = json.Unmarshal(bodyBytes, &translationResponse)
+	result, err := p.Do(func(ep pool.Endpoint) (pool.Result, error) {
+		httpReq, err := http.NewRequest(http.MethodPost, ep.URL, bytes.NewBuffer(requestBody))
+		if err != nil {
+			return pool.Result{}, fmt.Errorf("error building request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if ep.Token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+ep.Token)
+		}
+
+		client := &http.Client{Timeout: ep.Timeout}
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return pool.Result{}, fmt.Errorf("error sending request to DeepLX API: %w", err)
+		}
+		defer resp.Body.Close()
+
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return pool.Result{}, fmt.Errorf("error reading response body: %w", err)
+		}
+		return pool.Result{StatusCode: resp.StatusCode, Body: bodyBytes}, nil
+	})
 	if err != nil {
+		return "", err
+	}
+
+	var translationResponse TranslationResponse
+	if err := json.Unmarshal(result.Body, &translationResponse); err != nil {
 		return "", fmt.Errorf("error unmarshalling response: %w", err)
 	}
 
@@ -99,6 +264,12 @@ func translateText(text, sourceLang, targetLang, apiURL string) (string, error)
 		return "", fmt.Errorf("translation failed with code %d: %s", translationResponse.Code, translationResponse.Message)
 	}
 
+	if c != nil {
+		if err := c.Set(cacheKey, translationResponse.Data); err != nil {
+			log.Printf("Warning: failed to update translation cache: %v", err)
+		}
+	}
+
 	return translationResponse.Data, nil
 }
 
@@ -109,39 +280,41 @@ func main() {
 		log.Fatalf("failed to get current user home directory: %v", err)
 	}
 	configPath := filepath.Join(currentUser.HomeDir, configFileName)
+	defaultGlossaryPath := filepath.Join(currentUser.HomeDir, glossaryFileName)
 
-	// Load configuration
-	cfg := &Config{}
-	// Check if config file exists, generate default if not
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		log.Printf("Config file %s does not exist, generating default config.", configPath)
-		defaultConfig := &Config{
-			URL:        defaultDeepLXAPI,
-			SourceLang: "auto",
-			TargetLang: "EN",
+	// Dispatch the `glossary` and `translate-i18n` subcommands before flag
+	// parsing, since they take their own positional arguments/flag sets.
+	if len(os.Args) > 1 && os.Args[1] == "glossary" {
+		if err := runGlossaryCommand(defaultGlossaryPath, os.Args[2:]); err != nil {
+			log.Fatalf("glossary command failed: %v", err)
 		}
-		yamlData, err := yaml.Marshal(defaultConfig)
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "translate-i18n" {
+		i18nOpts, err := parseI18nArgs(os.Args[2:])
 		if err != nil {
-			log.Printf("Warning: Failed to marshal default config: %v", err)
-		} else {
-			err = os.WriteFile(configPath, yamlData, 0644)
-			if err != nil {
-				log.Printf("Warning: Failed to write default config file %s: %v", configPath, err)
-			} else {
-				log.Printf("Default config file generated: %s", configPath)
-			}
+			log.Fatalf("%v", err)
 		}
-		cfg = defaultConfig // Use default config as initial
-	} else if err != nil {
-		log.Fatalf("failed to check config file %s: %v", configPath, err)
+		cfg, err := loadConfigOrDefault(configPath)
+		if err != nil {
+			log.Fatalf("failed to load config: %v", err)
+		}
+		i18nPool := buildPool(cfg, pool.StrategyRoundRobin)
+		cacheDir := filepath.Join(currentUser.HomeDir, ".cache", cacheDirName)
+		i18nCache, err := cache.Open(cacheDir, cfg.CacheTTL, cfg.CacheMaxSize)
+		if err != nil {
+			log.Fatalf("failed to open translation cache: %v", err)
+		}
+		if err := runTranslateI18n(i18nOpts, i18nPool, i18nCache); err != nil {
+			log.Fatalf("translate-i18n failed: %v", err)
+		}
+		return
 	}
 
-	// Load config (either newly generated or existing)
-	loadedConfig, err := loadConfig(configPath)
+	// Load configuration, generating a default config file if none exists yet
+	cfg, err := loadConfigOrDefault(configPath)
 	if err != nil {
-		log.Printf("Warning: Failed to load or parse config file %s, using default values or command-line arguments: %v", configPath, err)
-	} else {
-		cfg = loadedConfig
+		log.Fatalf("failed to load config: %v", err)
 	}
 
 	// Define command-line flags
@@ -152,6 +325,16 @@ func main() {
 	var targetLangShortArg string // New variable for shorthand
 	var urlArg string
 	var versionFlag bool
+	var replFlag bool
+	var serveFlag bool
+	var serveAddrArg string
+	var fileArg string
+	var outFileArg string
+	var endpointStrategyArg string
+	var noCacheFlag bool
+	var cacheClearFlag bool
+	var cacheStatsFlag bool
+	var glossaryPathArg string
 
 	flag.StringVar(&textArg, "text", "", "Text to translate. If not provided, reads from standard input.")
 	flag.StringVar(&sourceLangArg, "source_lang", "", "Source language.")
@@ -161,6 +344,16 @@ func main() {
 	flag.StringVar(&urlArg, "url", "", "URL of the DeepLX API.")
 	flag.BoolVar(&versionFlag, "version", false, "Show version information.")
 	flag.BoolVar(&versionFlag, "v", false, "Show version information (shorthand).")
+	flag.BoolVar(&replFlag, "repl", false, "Start an interactive REPL session.")
+	flag.BoolVar(&serveFlag, "serve", false, "Run as a local HTTP daemon exposing a DeepLX-compatible endpoint.")
+	flag.StringVar(&serveAddrArg, "addr", ":9000", "Address to listen on when running with -serve.")
+	flag.StringVar(&fileArg, "file", "", "Translate a whole file (.txt, .md, .html, .srt), preserving inline markup.")
+	flag.StringVar(&outFileArg, "o", "", "Output path for -file mode. Defaults to <file>.<target_lang><ext>.")
+	flag.StringVar(&endpointStrategyArg, "endpoint-strategy", "roundrobin", "Endpoint selection strategy when multiple endpoints are configured: roundrobin, random, or failover.")
+	flag.BoolVar(&noCacheFlag, "no-cache", false, "Bypass the on-disk translation cache for this invocation.")
+	flag.BoolVar(&cacheClearFlag, "cache-clear", false, "Clear the on-disk translation cache and exit.")
+	flag.BoolVar(&cacheStatsFlag, "cache-stats", false, "Print translation cache statistics and exit.")
+	flag.StringVar(&glossaryPathArg, "glossary", "", "Path to a glossary YAML file. Defaults to ~/.deeplx-cli.glossary.yml.")
 
 	flag.Parse()
 
@@ -170,14 +363,46 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Determine final parameters with priority
-	finalURL := defaultDeepLXAPI
-	if cfg.URL != "" {
-		finalURL = cfg.URL
+	// Open the on-disk translation cache
+	cacheDir := filepath.Join(currentUser.HomeDir, ".cache", cacheDirName)
+	translationCache, err := cache.Open(cacheDir, cfg.CacheTTL, cfg.CacheMaxSize)
+	if err != nil {
+		log.Fatalf("failed to open translation cache: %v", err)
+	}
+
+	if cacheClearFlag {
+		if err := translationCache.Clear(); err != nil {
+			log.Fatalf("failed to clear translation cache: %v", err)
+		}
+		fmt.Println("translation cache cleared")
+		os.Exit(0)
+	}
+
+	if cacheStatsFlag {
+		stats := translationCache.Stats()
+		fmt.Printf("cache path: %s\ncache entries: %d\n", stats.Path, stats.Entries)
+		os.Exit(0)
+	}
+
+	if noCacheFlag {
+		translationCache = nil
+	}
+
+	glossaryPath := defaultGlossaryPath
+	if glossaryPathArg != "" {
+		glossaryPath = glossaryPathArg
+	}
+	glossary, err := loadGlossary(glossaryPath)
+	if err != nil {
+		log.Fatalf("failed to load glossary: %v", err)
 	}
+
+	// Determine final parameters with priority
 	if urlArg != "" {
-		finalURL = urlArg
+		cfg.URL = URLList{urlArg}
+		cfg.Endpoints = nil
 	}
+	translationPool := buildPool(cfg, pool.Strategy(endpointStrategyArg))
 
 	finalSourceLang := cfg.SourceLang
 	if sourceLangArg != "" {
@@ -197,6 +422,57 @@ func main() {
 		finalTargetLang = "EN" // Default target language
 	}
 
+	// Run as an HTTP daemon if requested
+	if serveFlag {
+		srvCfg := serveConfig{
+			addr:       serveAddrArg,
+			pool:       translationPool,
+			cache:      translationCache,
+			glossary:   glossary,
+			sourceLang: finalSourceLang,
+			targetLang: finalTargetLang,
+			authToken:  cfg.AuthToken,
+		}
+		if err := runServer(srvCfg); err != nil {
+			log.Fatalf("server failed: %v", err)
+		}
+		return
+	}
+
+	// Translate a whole file if requested
+	if fileArg != "" {
+		outPath := outFileArg
+		if outPath == "" {
+			ext := filepath.Ext(fileArg)
+			base := strings.TrimSuffix(fileArg, ext)
+			outPath = fmt.Sprintf("%s.%s%s", base, strings.ToLower(finalTargetLang), ext)
+		}
+		opts := docOptions{
+			pool:               translationPool,
+			cache:              translationCache,
+			sourceLang:         finalSourceLang,
+			targetLang:         finalTargetLang,
+			tagHandling:        cfg.TagHandling,
+			splittingTags:      cfg.SplittingTags,
+			nonSplittingTags:   cfg.NonSplittingTags,
+			ignoreTags:         cfg.IgnoreTags,
+			preserveFormatting: cfg.PreserveFormatting,
+		}
+		if err := translateDocument(fileArg, outPath, opts); err != nil {
+			log.Fatalf("document translation failed: %v", err)
+		}
+		fmt.Printf("translated %s -> %s\n", fileArg, outPath)
+		return
+	}
+
+	// Enter interactive REPL mode if requested
+	if replFlag {
+		if err := runREPL(translationPool, translationCache, glossary, finalSourceLang, finalTargetLang); err != nil {
+			log.Fatalf("REPL session failed: %v", err)
+		}
+		return
+	}
+
 	// Get text to translate
 	var inputText string
 	if textArg != "" {
@@ -224,7 +500,7 @@ func main() {
 	}
 
 	// Call translation function
-	translatedText, err := translateText(inputText, finalSourceLang, finalTargetLang, finalURL)
+	translatedText, err := translateTextWithGlossary(inputText, finalSourceLang, finalTargetLang, translationPool, translationCache, glossary)
 	if err != nil {
 		log.Fatalf("Translation failed: %v", err)
 	}
@@ -12,6 +12,8 @@ import (
 	"testing"
 
 	"github.com/atotto/clipboard"
+
+	"github.com/ubuygold/deeplx-cli/internal/pool"
 )
 
 const testConfig = `
@@ -33,8 +35,8 @@ func TestLoadConfig(t *testing.T) {
 		t.Fatalf("loadConfig failed: %v", err)
 	}
 
-	if cfg.URL != "http://localhost:8080" {
-		t.Errorf("Expected URL 'http://localhost:8080', got '%s'", cfg.URL)
+	if len(cfg.URL) != 1 || cfg.URL[0] != "http://localhost:8080" {
+		t.Errorf("Expected URL 'http://localhost:8080', got '%v'", cfg.URL)
 	}
 	if cfg.SourceLang != "auto" {
 		t.Errorf("Expected source_lang 'auto', got '%s'", cfg.SourceLang)
@@ -69,7 +71,8 @@ func TestTranslateText(t *testing.T) {
 	}))
 	defer server.Close()
 
-	result, err := translateText("hello", "auto", "ZH", server.URL)
+	p := pool.New([]pool.Endpoint{{URL: server.URL, Weight: 1}}, pool.StrategyFailover)
+	result, err := translateText("hello", "auto", "ZH", p, nil)
 	if err != nil {
 		t.Fatalf("translateText failed: %v", err)
 	}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chzyer/readline"
+
+	"github.com/ubuygold/deeplx-cli/internal/cache"
+	"github.com/ubuygold/deeplx-cli/internal/pool"
+)
+
+const historyFileName = ".deeplx-cli-history"
+
+// replSession holds the mutable state of an interactive translation session.
+type replSession struct {
+	sourceLang string
+	targetLang string
+	pool       *pool.Pool
+	cache      *cache.Store
+	glossary   Glossary
+	history    []string
+}
+
+// runREPL starts an interactive loop that reads lines from stdin, translates
+// them with the current source/target languages, and prints the result.
+// Lines beginning with ":" are treated as in-session commands.
+func runREPL(p *pool.Pool, c *cache.Store, g Glossary, sourceLang, targetLang string) error {
+	currentUser, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current user home directory: %w", err)
+	}
+	historyPath := filepath.Join(currentUser, historyFileName)
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          "deeplx> ",
+		HistoryFile:     historyPath,
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start readline: %w", err)
+	}
+	defer rl.Close()
+
+	sess := &replSession{
+		sourceLang: sourceLang,
+		targetLang: targetLang,
+		pool:       p,
+		cache:      c,
+		glossary:   g,
+	}
+
+	fmt.Println("Entering REPL mode. Type :help for a list of commands, :quit to exit.")
+
+	for {
+		line, err := rl.Readline()
+		if err == readline.ErrInterrupt {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading input: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			if quit := sess.handleCommand(line); quit {
+				return nil
+			}
+			continue
+		}
+
+		translated, err := translateTextWithGlossary(line, sess.sourceLang, sess.targetLang, sess.pool, sess.cache, sess.glossary)
+		if err != nil {
+			fmt.Printf("translation failed: %v\n", err)
+			continue
+		}
+
+		sess.history = append(sess.history, fmt.Sprintf("[%s->%s] %s => %s", sess.sourceLang, sess.targetLang, line, translated))
+		fmt.Println(translated)
+	}
+}
+
+// handleCommand executes a single ":"-prefixed REPL command. It returns true
+// if the session should exit.
+func (s *replSession) handleCommand(line string) bool {
+	fields := strings.Fields(line)
+	cmd := fields[0]
+	arg := ""
+	if len(fields) > 1 {
+		arg = strings.Join(fields[1:], " ")
+	}
+
+	switch cmd {
+	case ":quit", ":exit":
+		return true
+	case ":help":
+		fmt.Println(`Available commands:
+  :source <lang>   set the source language
+  :target <lang>   set the target language
+  :swap             swap source and target languages
+  :clear            clear the in-session history
+  :history          print the in-session history
+  :save <file>      save the in-session history to a file
+  :quit, :exit      leave the REPL`)
+	case ":source":
+		if arg == "" {
+			fmt.Println("usage: :source <lang>")
+			break
+		}
+		s.sourceLang = arg
+		fmt.Printf("source language set to %s\n", s.sourceLang)
+	case ":target":
+		if arg == "" {
+			fmt.Println("usage: :target <lang>")
+			break
+		}
+		s.targetLang = arg
+		fmt.Printf("target language set to %s\n", s.targetLang)
+	case ":swap":
+		s.sourceLang, s.targetLang = s.targetLang, s.sourceLang
+		fmt.Printf("source/target swapped: %s -> %s\n", s.sourceLang, s.targetLang)
+	case ":clear":
+		s.history = nil
+		fmt.Println("history cleared")
+	case ":history":
+		for _, h := range s.history {
+			fmt.Println(h)
+		}
+	case ":save":
+		if arg == "" {
+			fmt.Println("usage: :save <file>")
+			break
+		}
+		if err := s.saveHistory(arg); err != nil {
+			fmt.Printf("failed to save history: %v\n", err)
+		} else {
+			fmt.Printf("history saved to %s\n", arg)
+		}
+	default:
+		fmt.Printf("unknown command: %s (try :help)\n", cmd)
+	}
+	return false
+}
+
+// saveHistory writes the in-session translation history to path, one entry per line.
+func (s *replSession) saveHistory(path string) error {
+	content := strings.Join(s.history, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
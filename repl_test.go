@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandleCommandSourceTargetSwap(t *testing.T) {
+	sess := &replSession{sourceLang: "auto", targetLang: "EN"}
+
+	if quit := sess.handleCommand(":source FR"); quit {
+		t.Fatal(":source should not quit the session")
+	}
+	if sess.sourceLang != "FR" {
+		t.Errorf("expected sourceLang 'FR', got '%s'", sess.sourceLang)
+	}
+
+	if quit := sess.handleCommand(":target DE"); quit {
+		t.Fatal(":target should not quit the session")
+	}
+	if sess.targetLang != "DE" {
+		t.Errorf("expected targetLang 'DE', got '%s'", sess.targetLang)
+	}
+
+	if quit := sess.handleCommand(":swap"); quit {
+		t.Fatal(":swap should not quit the session")
+	}
+	if sess.sourceLang != "DE" || sess.targetLang != "FR" {
+		t.Errorf("expected source/target swapped to DE/FR, got %s/%s", sess.sourceLang, sess.targetLang)
+	}
+}
+
+func TestHandleCommandClearAndQuit(t *testing.T) {
+	sess := &replSession{history: []string{"one", "two"}}
+
+	if quit := sess.handleCommand(":clear"); quit {
+		t.Fatal(":clear should not quit the session")
+	}
+	if len(sess.history) != 0 {
+		t.Errorf("expected history cleared, got %v", sess.history)
+	}
+
+	if quit := sess.handleCommand(":quit"); !quit {
+		t.Error(":quit should quit the session")
+	}
+	if quit := sess.handleCommand(":exit"); !quit {
+		t.Error(":exit should quit the session")
+	}
+}
+
+func TestSaveHistory(t *testing.T) {
+	sess := &replSession{history: []string{"[auto->EN] 你好 => hello"}}
+	path := filepath.Join(t.TempDir(), "history.txt")
+
+	if err := sess.saveHistory(path); err != nil {
+		t.Fatalf("saveHistory failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read saved history: %v", err)
+	}
+	want := "[auto->EN] 你好 => hello\n"
+	if string(data) != want {
+		t.Errorf("expected %q, got %q", want, string(data))
+	}
+}
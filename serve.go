@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ubuygold/deeplx-cli/internal/cache"
+	"github.com/ubuygold/deeplx-cli/internal/pool"
+)
+
+// deeplxRequest mirrors the request body accepted by the upstream DeepLX
+// `POST /translate` endpoint.
+type deeplxRequest struct {
+	Text       string `json:"text"`
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+}
+
+// deeplxResponse mirrors the response body returned by the upstream DeepLX
+// `POST /translate` endpoint.
+type deeplxResponse struct {
+	Code         int      `json:"code"`
+	ID           int64    `json:"id"`
+	Data         string   `json:"data"`
+	SourceLang   string   `json:"source_lang"`
+	TargetLang   string   `json:"target_lang"`
+	Alternatives []string `json:"alternatives"`
+}
+
+// serveConfig holds the options needed to run the HTTP daemon.
+type serveConfig struct {
+	addr       string
+	pool       *pool.Pool
+	cache      *cache.Store
+	glossary   Glossary
+	sourceLang string
+	targetLang string
+	authToken  string
+}
+
+// runServer starts a local HTTP server implementing the DeepLX-compatible
+// `POST /translate` protocol and blocks until it receives a termination
+// signal, at which point it shuts down gracefully.
+func runServer(cfg serveConfig) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/translate", withCORS(cfg.requireAuth(cfg.handleTranslate)))
+
+	server := &http.Server{
+		Addr:    cfg.addr,
+		Handler: logRequests(mux),
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("deeplx-cli HTTP daemon listening on %s", cfg.addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server failed: %w", err)
+	case <-sigCh:
+		log.Println("shutting down server...")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+func (cfg serveConfig) handleTranslate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req deeplxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	sourceLang := req.SourceLang
+	if sourceLang == "" {
+		sourceLang = cfg.sourceLang
+	}
+	targetLang := req.TargetLang
+	if targetLang == "" {
+		targetLang = cfg.targetLang
+	}
+
+	translated, err := translateTextWithGlossary(req.Text, sourceLang, targetLang, cfg.pool, cfg.cache, cfg.glossary)
+	if err != nil {
+		log.Printf("translation failed: %v", err)
+		writeJSON(w, http.StatusOK, deeplxResponse{Code: http.StatusServiceUnavailable})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deeplxResponse{
+		Code:       http.StatusOK,
+		Data:       translated,
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+	})
+}
+
+// requireAuth wraps h with bearer-token auth when cfg.authToken is set.
+func (cfg serveConfig) requireAuth(h http.HandlerFunc) http.HandlerFunc {
+	if cfg.authToken == "" {
+		return h
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+cfg.authToken {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// withCORS allows the endpoint to be called from browser-based editor plugins.
+func withCORS(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// logRequests logs the method, path, and duration of every request handled.
+func logRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		h.ServeHTTP(w, r)
+		log.Printf("%s %s %s", r.Method, r.URL.Path, time.Since(start))
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ubuygold/deeplx-cli/internal/pool"
+)
+
+func TestHandleTranslate(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TranslationResponse{Code: 200, Data: "你好"})
+	}))
+	defer upstream.Close()
+
+	cfg := serveConfig{
+		pool:       pool.New([]pool.Endpoint{{URL: upstream.URL, Weight: 1}}, pool.StrategyFailover),
+		sourceLang: "auto",
+		targetLang: "ZH",
+	}
+
+	body, _ := json.Marshal(deeplxRequest{Text: "hello"})
+	req := httptest.NewRequest(http.MethodPost, "/translate", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	cfg.handleTranslate(rec, req)
+
+	var resp deeplxResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Code != http.StatusOK {
+		t.Errorf("expected code %d, got %d", http.StatusOK, resp.Code)
+	}
+	if resp.Data != "你好" {
+		t.Errorf("expected data '你好', got '%s'", resp.Data)
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	cfg := serveConfig{authToken: "secret"}
+	handler := cfg.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/translate", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d without token, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected %d with valid token, got %d", http.StatusOK, rec.Code)
+	}
+}
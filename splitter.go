@@ -0,0 +1,145 @@
+package main
+
+import "strings"
+
+// maxChunkRunes bounds the size of a single translation request so that large
+// documents stay comfortably under typical DeepLX/API length limits.
+const maxChunkRunes = 4000
+
+// splitParagraphs splits text into chunks no longer than maxLen runes
+// (maxChunkRunes when maxLen is not positive), preferring to break on blank-line (paragraph) boundaries and falling back
+// to sentence boundaries when a single paragraph exceeds the limit. The
+// original paragraph/sentence boundaries are preserved in the returned
+// chunks so the output can be rejoined losslessly.
+func splitParagraphs(text string, maxLen int) []string {
+	if maxLen <= 0 {
+		maxLen = maxChunkRunes
+	}
+
+	paragraphs := strings.SplitAfter(text, "\n\n")
+
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, p := range paragraphs {
+		if len([]rune(p)) > maxLen {
+			flush()
+			chunks = append(chunks, splitSentences(p, maxLen)...)
+			continue
+		}
+		if len([]rune(current.String()))+len([]rune(p)) > maxLen {
+			flush()
+		}
+		current.WriteString(p)
+	}
+	flush()
+
+	return chunks
+}
+
+// splitSentences splits an oversized paragraph on sentence boundaries
+// ('.', '!', '?' followed by whitespace), falling back to a hard rune cut if
+// a single sentence still exceeds maxLen.
+func splitSentences(text string, maxLen int) []string {
+	var sentences []string
+	var current strings.Builder
+
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		isBoundary := (runes[i] == '.' || runes[i] == '!' || runes[i] == '?') &&
+			(i+1 == len(runes) || runes[i+1] == ' ' || runes[i+1] == '\n')
+		if isBoundary {
+			sentences = append(sentences, string(runes[start:i+1]))
+			start = i + 1
+		}
+	}
+	if start < len(runes) {
+		sentences = append(sentences, string(runes[start:]))
+	}
+
+	var chunks []string
+	for _, s := range sentences {
+		if len([]rune(s)) > maxLen {
+			// Last resort: hard-cut a single overlong sentence.
+			for _, piece := range hardSplit(s, maxLen) {
+				chunks = append(chunks, piece)
+			}
+			continue
+		}
+		if len([]rune(current.String()))+len([]rune(s)) > maxLen {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(s)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// hardSplit cuts text into maxLen-rune pieces without regard to word or
+// sentence boundaries.
+func hardSplit(text string, maxLen int) []string {
+	runes := []rune(text)
+	var pieces []string
+	for len(runes) > 0 {
+		n := maxLen
+		if n > len(runes) {
+			n = len(runes)
+		}
+		pieces = append(pieces, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return pieces
+}
+
+// mergeSplitTags fixes up chunks produced by splitParagraphs/splitSentences
+// (which split purely on blank-line/sentence/rune boundaries, with no
+// awareness of markup) so that an HTML/XML tag is never left straddling two
+// chunks: if a chunk ends with an unclosed "<...>" tag, the rest of the tag
+// is pulled in from the chunk(s) that follow it.
+func mergeSplitTags(chunks []string) []string {
+	fixed := make([]string, 0, len(chunks))
+	for i := 0; i < len(chunks); i++ {
+		chunk := chunks[i]
+		for endsInOpenTag(chunk) && i+1 < len(chunks) {
+			next := chunks[i+1]
+			closeIdx := strings.IndexByte(next, '>')
+			if closeIdx == -1 {
+				// The closing '>' isn't in the very next chunk either;
+				// absorb all of it and keep looking further ahead.
+				chunk += next
+				chunks = append(chunks[:i+1], chunks[i+2:]...)
+				continue
+			}
+			chunk += next[:closeIdx+1]
+			rest := next[closeIdx+1:]
+			if rest == "" {
+				chunks = append(chunks[:i+1], chunks[i+2:]...)
+			} else {
+				chunks[i+1] = rest
+			}
+		}
+		fixed = append(fixed, chunk)
+	}
+	return fixed
+}
+
+// endsInOpenTag reports whether s ends partway through a "<...>" tag, i.e.
+// its last '<' has no matching '>' after it.
+func endsInOpenTag(s string) bool {
+	lastOpen := strings.LastIndexByte(s, '<')
+	if lastOpen == -1 {
+		return false
+	}
+	return strings.IndexByte(s[lastOpen:], '>') == -1
+}
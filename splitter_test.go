@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestSplitParagraphsRejoins(t *testing.T) {
+	text := "First paragraph.\n\nSecond paragraph.\n\nThird paragraph."
+	chunks := splitParagraphs(text, maxChunkRunes)
+
+	var rejoined string
+	for _, c := range chunks {
+		rejoined += c
+	}
+	if rejoined != text {
+		t.Errorf("rejoined chunks do not match original text:\ngot:  %q\nwant: %q", rejoined, text)
+	}
+}
+
+func TestSplitParagraphsRespectsMaxLen(t *testing.T) {
+	text := "aaaaaaaaaa\n\nbbbbbbbbbb\n\ncccccccccc"
+	chunks := splitParagraphs(text, 12)
+
+	for _, c := range chunks {
+		if len([]rune(c)) > 12 {
+			t.Errorf("chunk %q exceeds max length of 12", c)
+		}
+	}
+}
+
+func TestMergeSplitTagsReassemblesSplitTag(t *testing.T) {
+	// Simulate a markup-unaware split that cut right inside a tag's
+	// attribute value, the way splitSentences would on a "." mid-attribute.
+	chunks := []string{`<meta name="description" content="Welcome.`, ` Read more">`}
+
+	merged := mergeSplitTags(chunks)
+
+	if len(merged) != 1 {
+		t.Fatalf("expected the split tag to be merged into one chunk, got %d: %v", len(merged), merged)
+	}
+	want := `<meta name="description" content="Welcome. Read more">`
+	if merged[0] != want {
+		t.Errorf("expected merged chunk %q, got %q", want, merged[0])
+	}
+}
+
+func TestMergeSplitTagsLeavesCleanBoundariesAlone(t *testing.T) {
+	chunks := []string{"<p>hello</p>", "<p>world</p>"}
+	merged := mergeSplitTags(chunks)
+	if len(merged) != 2 || merged[0] != chunks[0] || merged[1] != chunks[1] {
+		t.Errorf("expected chunks untouched, got %v", merged)
+	}
+}